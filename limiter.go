@@ -48,6 +48,15 @@ func (l *Limiter) SetBurst(burst float64) {
 	l.burst = burst
 }
 
+// burstSnapshot returns the current burst size under l.mu, for internal
+// callers (e.g. the Reader/Writer wrappers) that must not read the field
+// directly.
+func (l *Limiter) burstSnapshot() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.burst
+}
+
 // TokensAt returns tokens at time point t
 func (l *Limiter) TokensAt(t time.Time) float64 {
 	l.mu.Lock()
@@ -65,9 +74,16 @@ func (l *Limiter) Allow() bool {
 	return l.AllowN(1)
 }
 
-// AllowN judges if n tokens are availiable now
+// AllowN judges if n tokens are availiable now, and if so, consumes them.
 func (l *Limiter) AllowN(n float64) bool {
-	_, err := l.OccupyTokens(time.Now(), 0, n)
+	return l.AllowAt(time.Now(), n)
+}
+
+// AllowAt is AllowN but takes an explicit time t instead of hard-coding
+// time.Now(), so a caller with a fake clock can exercise the limiter's
+// allow decision deterministically.
+func (l *Limiter) AllowAt(t time.Time, n float64) bool {
+	_, err := l.OccupyTokens(t, 0, n)
 	return err == nil
 }
 
@@ -78,6 +94,13 @@ func (l *Limiter) Wait(ctx context.Context) error {
 
 // WaitN waits until n tokens are available and returns nil, or waits until ctx is cancelled and returns err.
 func (l *Limiter) WaitN(ctx context.Context, n float64) error {
+	return l.WaitAt(ctx, time.Now(), n)
+}
+
+// WaitAt is WaitN but takes an explicit start time t instead of hard-coding
+// time.Now(), so a caller with a fake clock can exercise the limiter's wait
+// behaviour deterministically.
+func (l *Limiter) WaitAt(ctx context.Context, t time.Time, n float64) error {
 	// check if ctx is already cancelled
 	select {
 	case <-ctx.Done():
@@ -85,7 +108,7 @@ func (l *Limiter) WaitN(ctx context.Context, n float64) error {
 	default:
 	}
 
-	o, err := l.OccupyTokens(time.Now(), InfDuration, n)
+	o, err := l.OccupyTokens(t, InfDuration, n)
 	if err != nil {
 		return err
 	}
@@ -95,10 +118,10 @@ func (l *Limiter) WaitN(ctx context.Context, n float64) error {
 	if delay == 0 {
 		return nil
 	}
-	t := time.NewTimer(delay)
-	defer t.Stop()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
 	select {
-	case <-t.C:
+	case <-timer.C:
 		// We can proceed.
 		return nil
 	case <-ctx.Done():
@@ -0,0 +1,46 @@
+package watchdog
+
+import (
+	"context"
+	"io"
+)
+
+// reader wraps an io.Reader and throttles Read against a Limiter configured
+// in bytes/second.
+type reader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *Limiter
+}
+
+// NewReader returns an io.Reader that reads from r, blocking as necessary so
+// that the aggregate read rate does not exceed l.
+func NewReader(r io.Reader, l *Limiter) io.Reader {
+	return NewReaderContext(context.Background(), r, l)
+}
+
+// NewReaderContext is like NewReader, but the wait before each chunk is tied
+// to ctx, so a cancelled ctx unblocks a Read that is waiting for tokens.
+func NewReaderContext(ctx context.Context, r io.Reader, l *Limiter) io.Reader {
+	return &reader{ctx: ctx, r: r, l: l}
+}
+
+// Read reads at most l's burst size at a time from the underlying reader,
+// then waits for the tokens corresponding to the bytes actually returned.
+// Charging for the requested size rather than the actual one would
+// over-throttle short reads (sockets, pipes, HTTP bodies), so the wait
+// happens after the read, not before.
+func (r *reader) Read(buf []byte) (int, error) {
+	if max := int(r.l.burstSnapshot()); max > 0 && len(buf) > max {
+		buf = buf[:max]
+	}
+
+	n, err := r.r.Read(buf)
+	if n <= 0 {
+		return n, err
+	}
+	if werr := r.l.WaitN(r.ctx, float64(n)); werr != nil {
+		return n, werr
+	}
+	return n, err
+}
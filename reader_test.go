@@ -0,0 +1,74 @@
+package watchdog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+// shortReader returns at most n bytes per Read, regardless of len(buf), to
+// exercise the "real read rate, not requested size" accounting.
+type shortReader struct {
+	data []byte
+	n    int
+}
+
+func (r *shortReader) Read(buf []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := r.n
+	if n > len(buf) {
+		n = len(buf)
+	}
+	if n > len(r.data) {
+		n = len(r.data)
+	}
+	copy(buf, r.data[:n])
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestReaderChargesActualBytes(t *testing.T) {
+	src := &shortReader{data: bytes.Repeat([]byte("a"), 50), n: 1}
+	l := NewLimiter(100, 50) // 100 B/s, burst 50: bucket starts full
+
+	begin := time.Now()
+	r := NewReader(src, l)
+	buf := new(bytes.Buffer)
+	if _, err := io.Copy(buf, r); err != nil {
+		t.Fatalf("io.Copy: %v", err)
+	}
+	elapsed := time.Since(begin)
+
+	if buf.Len() != 50 {
+		t.Fatalf("got %d bytes, want 50", buf.Len())
+	}
+	// Burst covers all 50 bytes from the full bucket, so draining a
+	// 1-byte-per-Read source should finish quickly, not in lockstep with
+	// len(buf) requested per call.
+	if elapsed > 2*time.Second {
+		t.Fatalf("took %v, over-throttled on requested chunk size instead of actual bytes read", elapsed)
+	}
+}
+
+func TestReaderContextCancel(t *testing.T) {
+	src := bytes.NewReader(bytes.Repeat([]byte("x"), 10))
+	l := NewLimiter(1, 1) // 1 B/s, burst 1: second byte must wait ~1s
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := NewReaderContext(ctx, src, l)
+
+	buf := make([]byte, 10)
+	n, err := r.Read(buf)
+	if err != nil || n != 1 {
+		t.Fatalf("first Read: n=%d err=%v", n, err)
+	}
+
+	cancel()
+	if _, err := r.Read(buf); err != context.Canceled {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,50 @@
+package watchdog
+
+import "time"
+
+// Reserve is shorthand for ReserveN(time.Now(), 1).
+func (l *Limiter) Reserve() *Occupancy {
+	return l.ReserveN(time.Now(), 1)
+}
+
+// ReserveN returns an Occupancy for n tokens starting at time t. Unlike
+// OccupyTokens, ReserveN never returns an error: a reservation that cannot
+// ever be honoured (n exceeds l's burst) comes back as an Occupancy whose OK
+// reports false rather than as an error, matching golang.org/x/time/rate's
+// Reservation.
+//
+// Migrating from golang.org/x/time/rate is close to mechanical:
+//
+//	rate.NewLimiter(limit, burst) -> watchdog.NewLimiter(limit, burst)
+//	lim.Allow()                   -> l.Allow()
+//	lim.AllowN(now, n)            -> l.AllowN(n) or l.AllowAt(now, n)
+//	lim.Reserve()                 -> l.Reserve()
+//	lim.ReserveN(now, n)          -> l.ReserveN(now, n)
+//	lim.Wait(ctx)                 -> l.Wait(ctx)
+//	lim.WaitN(ctx, n)             -> l.WaitN(ctx, n) or l.WaitAt(ctx, now, n)
+//	r.OK()                        -> o.OK()
+//	r.Delay()                     -> o.DelayFrom(time.Now())
+//	r.DelayFrom(now)              -> o.DelayFrom(now)
+//	r.Cancel()                    -> o.Cancel()
+//	r.CancelAt(now)               -> o.CancelAt(now)
+func (l *Limiter) ReserveN(t time.Time, n float64) *Occupancy {
+	o, err := l.OccupyTokens(t, InfDuration, n)
+	if err != nil {
+		// Not honourable: n exceeds burst and can never be satisfied.
+		return &Occupancy{cancelled: true}
+	}
+	return o
+}
+
+// OK reports whether the Occupancy is honourable, i.e. whether the reserved
+// tokens can ever be supplied by the limiter. A false OK means more tokens
+// were requested than the limiter's burst allows; the caller should act as
+// if the event was not allowed, and must not wait on or Cancel it.
+func (o *Occupancy) OK() bool {
+	return o.limiter != nil
+}
+
+// Act returns the time at which the reserved event should happen.
+func (o *Occupancy) Act() time.Time {
+	return o.timeToAct
+}
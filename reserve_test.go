@@ -0,0 +1,56 @@
+package watchdog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReserveIsHonourable(t *testing.T) {
+	l := NewLimiter(10, 5)
+	o := l.Reserve()
+	if !o.OK() {
+		t.Fatal("Reserve() should be honourable from a fresh, full bucket")
+	}
+	if o.Act().After(time.Now().Add(time.Millisecond)) {
+		t.Fatalf("Act() = %v, want ~now", o.Act())
+	}
+}
+
+func TestReserveNOverBurstIsNotOK(t *testing.T) {
+	l := NewLimiter(10, 5)
+	o := l.ReserveN(time.Now(), 100)
+	if o.OK() {
+		t.Fatal("ReserveN() for more tokens than burst should not be OK")
+	}
+}
+
+func TestReserveNActSchedulesFutureTokens(t *testing.T) {
+	l := NewLimiter(10, 5) // 10 tokens/s, burst 5
+	now := time.Now()
+
+	l.ReserveN(now, 5) // drains the bucket
+	o := l.ReserveN(now, 5)
+	if !o.OK() {
+		t.Fatal("a second full-burst reservation should still be honourable, just delayed")
+	}
+	if delay := o.DelayFrom(now); delay < 400*time.Millisecond {
+		t.Fatalf("DelayFrom(now) = %v, want close to burst/limit = 500ms", delay)
+	}
+}
+
+func TestReserveNCancelGivesTokensBack(t *testing.T) {
+	l := NewLimiter(10, 5)
+	now := time.Now()
+
+	o1 := l.ReserveN(now, 5) // drains the bucket
+	o2 := l.ReserveN(now, 1)
+	before := o2.DelayFrom(now)
+
+	o1.CancelAt(now)
+
+	o3 := l.ReserveN(now, 1)
+	after := o3.DelayFrom(now)
+	if after >= before {
+		t.Fatalf("cancelling o1 should free tokens for later reservations: before=%v after=%v", before, after)
+	}
+}
@@ -0,0 +1,50 @@
+package watchdog
+
+import (
+	"sync"
+	"time"
+)
+
+// Sometimes is a primitive for running code occasionally inside a hot loop,
+// e.g. throttling log spam or sampling stats, as opposed to Limiter which
+// gates events against a token bucket and can make the caller wait. Do
+// never blocks: it either runs the function now or skips it.
+//
+// Sometimes composes three independent policies; Do runs f if any of them
+// fires:
+//
+//	First    run the first N calls
+//	Every    run every Nth call
+//	Interval run at most once per Interval
+//
+// A zero Sometimes with all fields unset never runs f.
+type Sometimes struct {
+	First    int
+	Every    int
+	Interval time.Duration
+
+	mu    sync.Mutex
+	count int
+	last  time.Time
+}
+
+// Do runs f if First, Every, or Interval indicates that it's time, and
+// tracks the counter/last-run state needed to evaluate those policies.
+func (s *Sometimes) Do(f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.shouldDo() {
+		f()
+		s.last = time.Now()
+	}
+	s.count++
+}
+
+// shouldDo reports whether any of s's policies fires for the current call.
+// It must be called with s.mu held.
+func (s *Sometimes) shouldDo() bool {
+	return s.First > 0 && s.count < s.First ||
+		s.Every > 0 && s.count%s.Every == 0 ||
+		s.Interval > 0 && time.Since(s.last) >= s.Interval
+}
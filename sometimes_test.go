@@ -0,0 +1,80 @@
+package watchdog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSometimesFirst(t *testing.T) {
+	s := Sometimes{First: 3}
+	ran := 0
+	for i := 0; i < 10; i++ {
+		s.Do(func() { ran++ })
+	}
+	if ran != 3 {
+		t.Fatalf("ran = %d, want 3", ran)
+	}
+}
+
+func TestSometimesEvery(t *testing.T) {
+	s := Sometimes{Every: 3}
+	var calls []int
+	for i := 0; i < 9; i++ {
+		i := i
+		s.Do(func() { calls = append(calls, i) })
+	}
+	want := []int{0, 3, 6}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i, c := range calls {
+		if c != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+}
+
+func TestSometimesInterval(t *testing.T) {
+	s := Sometimes{Interval: 20 * time.Millisecond}
+	ran := 0
+	s.Do(func() { ran++ }) // last is zero-value, so this always fires
+	s.Do(func() { ran++ }) // too soon since the previous run
+	if ran != 1 {
+		t.Fatalf("ran = %d, want 1", ran)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	s.Do(func() { ran++ }) // interval elapsed
+	if ran != 2 {
+		t.Fatalf("ran = %d, want 2", ran)
+	}
+}
+
+func TestSometimesCombinedPolicies(t *testing.T) {
+	// First lets the first call through even though Every and Interval
+	// wouldn't fire yet on their own.
+	s := Sometimes{First: 1, Every: 5, Interval: time.Hour}
+	ran := 0
+	for i := 0; i < 5; i++ {
+		s.Do(func() { ran++ })
+	}
+	if ran != 1 {
+		t.Fatalf("ran = %d, want 1 (only the First call, counts 0-4)", ran)
+	}
+
+	s.Do(func() { ran++ }) // count is now 5, so Every fires
+	if ran != 2 {
+		t.Fatalf("ran = %d, want 2 (Every fired once count reached 5)", ran)
+	}
+}
+
+func TestSometimesZeroValueNeverRuns(t *testing.T) {
+	var s Sometimes
+	ran := 0
+	for i := 0; i < 5; i++ {
+		s.Do(func() { ran++ })
+	}
+	if ran != 0 {
+		t.Fatalf("ran = %d, want 0", ran)
+	}
+}
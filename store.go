@@ -0,0 +1,241 @@
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// storeShardCount is the number of shards a LimiterStore splits its keys
+// across, so that throttling one key does not contend with throttling
+// another under heavy concurrency.
+const storeShardCount = 32
+
+// fnvOffset32 and fnvPrime32 are the 32-bit FNV-1a constants, inlined here
+// so shardFor can hash a key without going through hash.Hash (which heap
+// allocates its state) or fmt (which reflects over the value).
+const (
+	fnvOffset32 = 2166136261
+	fnvPrime32  = 16777619
+)
+
+// LimiterStore maps arbitrary keys (user ID, IP, API token, ...) to their
+// own Limiter, created lazily on first use with the store's default limit
+// and burst. It is the per-tenant throttling pattern that HTTP middleware
+// usually reimplements by hand on top of a bare Limiter.
+type LimiterStore[K comparable] struct {
+	shards [storeShardCount]*storeShard[K]
+	hash   func(K) uint32
+
+	mu    sync.RWMutex // protects limit and burst
+	limit float64
+	burst float64
+
+	idleTTL time.Duration
+	done    chan struct{}
+	closed  sync.Once
+}
+
+type storeShard[K comparable] struct {
+	mu      sync.RWMutex
+	entries map[K]*Limiter
+}
+
+// NewLimiterStore creates a LimiterStore whose Limiters default to the given
+// limit and burst. If idleTTL is positive, a background janitor evicts keys
+// whose Limiter has had a full, untouched bucket for at least idleTTL; a
+// zero idleTTL disables the janitor and entries live for the store's life.
+//
+// Keys are sharded with hashKey, which special-cases string and the integer
+// kinds; for any other K, or to avoid hashKey's per-call interface
+// conversion on the hot path, use NewLimiterStoreWithHash with a hash
+// function tailored to K.
+func NewLimiterStore[K comparable](limit, burst float64, idleTTL time.Duration) *LimiterStore[K] {
+	return NewLimiterStoreWithHash[K](limit, burst, idleTTL, hashKey[K])
+}
+
+// NewLimiterStoreWithHash is NewLimiterStore but with a caller-supplied hash
+// function for sharding keys, letting performance-sensitive callers bypass
+// hashKey's generic-to-interface conversion entirely by hashing K directly
+// (e.g. a closure around fnv32aString for a K that is a defined string type).
+func NewLimiterStoreWithHash[K comparable](limit, burst float64, idleTTL time.Duration, hash func(K) uint32) *LimiterStore[K] {
+	s := &LimiterStore[K]{
+		hash:    hash,
+		limit:   limit,
+		burst:   burst,
+		idleTTL: idleTTL,
+		done:    make(chan struct{}),
+	}
+	for i := range s.shards {
+		s.shards[i] = &storeShard[K]{entries: make(map[K]*Limiter)}
+	}
+	if idleTTL > 0 {
+		go s.runJanitor()
+	}
+	return s
+}
+
+// shardFor picks key's shard via s.hash.
+func (s *LimiterStore[K]) shardFor(key K) *storeShard[K] {
+	return s.shards[s.hash(key)%storeShardCount]
+}
+
+func hashKey[K comparable](key K) uint32 {
+	switch k := any(key).(type) {
+	case string:
+		return fnv32aString(k)
+	case int:
+		return fnv32aUint64(uint64(k))
+	case int8:
+		return fnv32aUint64(uint64(k))
+	case int16:
+		return fnv32aUint64(uint64(k))
+	case int32:
+		return fnv32aUint64(uint64(k))
+	case int64:
+		return fnv32aUint64(uint64(k))
+	case uint:
+		return fnv32aUint64(uint64(k))
+	case uint8:
+		return fnv32aUint64(uint64(k))
+	case uint16:
+		return fnv32aUint64(uint64(k))
+	case uint32:
+		return fnv32aUint64(uint64(k))
+	case uint64:
+		return fnv32aUint64(k)
+	default:
+		return fnv32aString(fmt.Sprintf("%v", k))
+	}
+}
+
+func fnv32aString(s string) uint32 {
+	h := uint32(fnvOffset32)
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= fnvPrime32
+	}
+	return h
+}
+
+func fnv32aUint64(v uint64) uint32 {
+	h := uint32(fnvOffset32)
+	for i := 0; i < 8; i++ {
+		h ^= uint32(byte(v))
+		h *= fnvPrime32
+		v >>= 8
+	}
+	return h
+}
+
+// Get returns key's Limiter, creating it with the store's current defaults
+// if this is the first time key has been seen.
+func (s *LimiterStore[K]) Get(key K) *Limiter {
+	shard := s.shardFor(key)
+
+	shard.mu.RLock()
+	l, ok := shard.entries[key]
+	shard.mu.RUnlock()
+	if ok {
+		return l
+	}
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	if l, ok = shard.entries[key]; ok {
+		return l
+	}
+
+	s.mu.RLock()
+	limit, burst := s.limit, s.burst
+	s.mu.RUnlock()
+
+	l = NewLimiter(limit, burst)
+	shard.entries[key] = l
+	return l
+}
+
+// Allow is shorthand for Get(key).Allow().
+func (s *LimiterStore[K]) Allow(key K) bool {
+	return s.Get(key).Allow()
+}
+
+// Wait is shorthand for Get(key).Wait(ctx).
+func (s *LimiterStore[K]) Wait(ctx context.Context, key K) error {
+	return s.Get(key).Wait(ctx)
+}
+
+// Len returns the number of keys currently tracked by the store.
+func (s *LimiterStore[K]) Len() int {
+	n := 0
+	for _, shard := range s.shards {
+		shard.mu.RLock()
+		n += len(shard.entries)
+		shard.mu.RUnlock()
+	}
+	return n
+}
+
+// SetDefaults changes the limit and burst used for keys created from now on.
+// Existing keys' Limiters are left untouched; use SetPerKey to override one
+// of them.
+func (s *LimiterStore[K]) SetDefaults(limit, burst float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = limit
+	s.burst = burst
+}
+
+// SetPerKey overrides limit and burst for key's Limiter, creating it first
+// if key hasn't been seen yet. Use this to relax or tighten a single hot
+// tenant without replacing the whole store.
+func (s *LimiterStore[K]) SetPerKey(key K, limit, burst float64) {
+	l := s.Get(key)
+	l.SetLimit(limit)
+	l.SetBurst(burst)
+}
+
+// Stop terminates the background janitor goroutine, if one was started. It
+// is safe to call Stop more than once.
+func (s *LimiterStore[K]) Stop() {
+	s.closed.Do(func() { close(s.done) })
+}
+
+func (s *LimiterStore[K]) runJanitor() {
+	ticker := time.NewTicker(s.idleTTL)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			s.evictIdle(now)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *LimiterStore[K]) evictIdle(now time.Time) {
+	for _, shard := range s.shards {
+		shard.mu.Lock()
+		for key, l := range shard.entries {
+			if l.idleSince(now, s.idleTTL) {
+				delete(shard.entries, key)
+			}
+		}
+		shard.mu.Unlock()
+	}
+}
+
+// idleSince reports whether l's bucket has been full for at least ttl as of
+// now, i.e. zeroPoint indicates no tokens have been drawn from it recently.
+func (l *Limiter) idleSince(now time.Time, ttl time.Duration) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fullAt := l.zeroPoint.Add(durationFromTokens(l.burst, l.limit))
+	if now.Before(fullAt) {
+		return false
+	}
+	return now.Sub(fullAt) >= ttl
+}
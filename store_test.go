@@ -0,0 +1,90 @@
+package watchdog
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLimiterStoreGetIsLazyAndStable(t *testing.T) {
+	s := NewLimiterStore[string](10, 2, 0)
+
+	a := s.Get("a")
+	if a == nil {
+		t.Fatal("Get returned nil")
+	}
+	if s.Get("a") != a {
+		t.Fatal("Get returned a different Limiter for the same key")
+	}
+	if s.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", s.Len())
+	}
+
+	s.Get("b")
+	if s.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", s.Len())
+	}
+}
+
+func TestLimiterStoreAllowWait(t *testing.T) {
+	s := NewLimiterStore[string](10, 1, 0)
+	if !s.Allow("k") {
+		t.Fatal("first Allow should succeed from a full bucket")
+	}
+	if err := s.Wait(context.Background(), "k"); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+}
+
+func TestLimiterStoreSetPerKeyOverridesOnlyThatKey(t *testing.T) {
+	s := NewLimiterStore[string](10, 1, 0)
+
+	s.SetPerKey("hot", 1000, 5)
+	time.Sleep(20 * time.Millisecond)
+	s.Get("cold") // created fresh, after the sleep, with the store's defaults
+
+	hotTokens := s.Get("hot").TokensAt(time.Now())
+	coldTokens := s.Get("cold").TokensAt(time.Now())
+
+	if hotTokens < 4 {
+		t.Fatalf("hot key's burst override was not applied, tokens=%v", hotTokens)
+	}
+	if coldTokens > 1 {
+		t.Fatalf("cold key was unexpectedly affected by hot's override, tokens=%v", coldTokens)
+	}
+}
+
+func TestLimiterStoreConcurrentGet(t *testing.T) {
+	s := NewLimiterStore[int](10, 2, 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.Get(i % 10)
+		}(i)
+	}
+	wg.Wait()
+	if s.Len() != 10 {
+		t.Fatalf("Len() = %d, want 10", s.Len())
+	}
+}
+
+func TestLimiterStoreJanitorEvictsIdleKeys(t *testing.T) {
+	s := NewLimiterStore[string](1000, 1, 5*time.Millisecond)
+	defer s.Stop()
+
+	s.Get("idle")
+	if s.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", s.Len())
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for s.Len() != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if s.Len() != 0 {
+		t.Fatalf("janitor did not evict idle key, Len() = %d", s.Len())
+	}
+}
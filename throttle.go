@@ -0,0 +1,114 @@
+package watchdog
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Throttle paces repeated attempts at an operation that may fail, e.g.
+// reconnecting to a peer, so a tight retry loop over the same Throttle is
+// naturally spaced out instead of hammering the remote end. It is patterned
+// after the reconnection throttling in the etcd v3 client.
+//
+// Internally a Throttle is a Limiter with burst 1 and limit 1/minWait (or
+// 1/current wait, in backoff mode); Do draws a token from it only when fn
+// fails, so successful calls are never delayed.
+type Throttle struct {
+	limiter *Limiter
+
+	backoff bool
+	base    time.Duration
+	max     time.Duration
+	factor  float64
+
+	mu      sync.Mutex
+	current time.Duration
+}
+
+// NewThrottle creates a Throttle that spaces consecutive failed attempts at
+// least minWait apart.
+func NewThrottle(minWait time.Duration) *Throttle {
+	return &Throttle{
+		limiter: NewLimiter(waitRate(minWait), 1),
+		current: minWait,
+	}
+}
+
+// WithBackoff switches t into exponential-backoff mode: each attempt that
+// fails multiplies the wait before the next one by factor, up to max, and a
+// successful attempt resets it back to base. It returns t for chaining.
+func (t *Throttle) WithBackoff(base, max time.Duration, factor float64) *Throttle {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.backoff = true
+	t.base = base
+	t.max = max
+	t.factor = factor
+	t.current = base
+	t.limiter.SetLimit(waitRate(base))
+	return t
+}
+
+// Do invokes fn. If fn fails, Do blocks until at least the current wait has
+// elapsed since Do was called, or ctx is cancelled, before returning fn's
+// error; ctx cancellation returns ctx.Err() instead. A successful fn resets
+// the backoff, if enabled, and returns immediately.
+func (t *Throttle) Do(ctx context.Context, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	if err == nil {
+		t.onSuccess()
+		return nil
+	}
+
+	o, rerr := t.limiter.OccupyTokens(start, InfDuration, 1)
+	if rerr != nil {
+		// minWait is 0 or negative: nothing to wait for.
+		t.onFailure()
+		return err
+	}
+	if delay := o.DelayFrom(time.Now()); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			o.Cancel()
+			return ctx.Err()
+		}
+	}
+
+	t.onFailure()
+	return err
+}
+
+func (t *Throttle) onSuccess() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.backoff && t.current != t.base {
+		t.current = t.base
+		t.limiter.SetLimit(waitRate(t.current))
+	}
+}
+
+func (t *Throttle) onFailure() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.backoff {
+		return
+	}
+	next := time.Duration(float64(t.current) * t.factor)
+	if next > t.max {
+		next = t.max
+	}
+	t.current = next
+	t.limiter.SetLimit(waitRate(t.current))
+}
+
+// waitRate converts a minimum wait duration into the equivalent
+// tokens-per-second limit for a burst-1 Limiter.
+func waitRate(minWait time.Duration) float64 {
+	return 1 / minWait.Seconds()
+}
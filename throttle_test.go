@@ -0,0 +1,87 @@
+package watchdog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestThrottleSpacesOutFailures(t *testing.T) {
+	th := NewThrottle(20 * time.Millisecond)
+	ctx := context.Background()
+	failFn := func() error { return errors.New("boom") }
+
+	// The bucket starts full, so the first failure shouldn't wait.
+	th.Do(ctx, failFn)
+
+	begin := time.Now()
+	if err := th.Do(ctx, failFn); err == nil {
+		t.Fatal("expected fn's error to propagate")
+	}
+	if elapsed := time.Since(begin); elapsed < 15*time.Millisecond {
+		t.Fatalf("second failing Do returned after %v, want >= minWait", elapsed)
+	}
+}
+
+func TestThrottleDoesNotDelaySuccess(t *testing.T) {
+	th := NewThrottle(time.Second)
+	ctx := context.Background()
+
+	begin := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := th.Do(ctx, func() error { return nil }); err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+	}
+	if elapsed := time.Since(begin); elapsed > 200*time.Millisecond {
+		t.Fatalf("successful Do calls were throttled, took %v", elapsed)
+	}
+}
+
+func TestThrottleContextCancel(t *testing.T) {
+	th := NewThrottle(time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	th.Do(ctx, func() error { return errors.New("boom") }) // drains the initial burst
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	if err := th.Do(ctx, func() error { return errors.New("boom") }); err != context.Canceled {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+}
+
+func TestThrottleBackoffGrowsAndResets(t *testing.T) {
+	th := NewThrottle(5 * time.Millisecond).WithBackoff(5*time.Millisecond, 200*time.Millisecond, 4)
+	ctx := context.Background()
+	failFn := func() error { return errors.New("boom") }
+
+	th.Do(ctx, failFn) // consumes the initial burst; current grows from base to base*factor
+
+	begin := time.Now()
+	th.Do(ctx, failFn) // waits ~base*factor, then current grows to base*factor^2
+	firstWait := time.Since(begin)
+
+	begin = time.Now()
+	th.Do(ctx, failFn) // waits ~base*factor^2
+	secondWait := time.Since(begin)
+
+	if secondWait <= firstWait {
+		t.Fatalf("backoff did not grow: first=%v second=%v", firstWait, secondWait)
+	}
+
+	if err := th.Do(ctx, func() error { return nil }); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+
+	begin = time.Now()
+	th.Do(ctx, failFn)
+	resetWait := time.Since(begin)
+	if resetWait >= secondWait {
+		t.Fatalf("backoff did not reset after success: reset=%v second=%v", resetWait, secondWait)
+	}
+}
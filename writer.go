@@ -0,0 +1,50 @@
+package watchdog
+
+import (
+	"context"
+	"io"
+)
+
+// writer wraps an io.Writer and throttles Write against a Limiter configured
+// in bytes/second.
+type writer struct {
+	ctx context.Context
+	w   io.Writer
+	l   *Limiter
+}
+
+// NewWriter returns an io.Writer that writes to w, blocking as necessary so
+// that the aggregate write rate does not exceed l.
+func NewWriter(w io.Writer, l *Limiter) io.Writer {
+	return NewWriterContext(context.Background(), w, l)
+}
+
+// NewWriterContext is like NewWriter, but the wait before each chunk is tied
+// to ctx, so a cancelled ctx unblocks a Write that is waiting for tokens.
+func NewWriterContext(ctx context.Context, w io.Writer, l *Limiter) io.Writer {
+	return &writer{ctx: ctx, w: w, l: l}
+}
+
+// Write splits buf into chunks of at most l's burst size, waiting for each
+// chunk's tokens to become available before handing it to the underlying
+// writer.
+func (w *writer) Write(buf []byte) (int, error) {
+	max := int(w.l.burstSnapshot())
+	written := 0
+	for len(buf) > 0 {
+		chunk := buf
+		if max > 0 && len(chunk) > max {
+			chunk = chunk[:max]
+		}
+		if err := w.l.WaitN(w.ctx, float64(len(chunk))); err != nil {
+			return written, err
+		}
+		n, err := w.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		buf = buf[n:]
+	}
+	return written, nil
+}
@@ -0,0 +1,38 @@
+package watchdog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestWriterRoundTrip(t *testing.T) {
+	l := NewLimiter(1000000, 4) // large burst relative to payload, should not block
+	out := new(bytes.Buffer)
+	w := NewWriter(out, l)
+
+	payload := []byte("a longer message than the burst size")
+	n, err := w.Write(payload)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if n != len(payload) {
+		t.Fatalf("wrote %d bytes, want %d", n, len(payload))
+	}
+	if out.String() != string(payload) {
+		t.Fatalf("got %q, want %q", out.String(), payload)
+	}
+}
+
+func TestWriterContextCancel(t *testing.T) {
+	l := NewLimiter(1, 1) // 1 B/s, burst 1
+	out := new(bytes.Buffer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := NewWriterContext(ctx, out, l)
+
+	if _, err := w.Write([]byte("ab")); err != context.Canceled {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+}